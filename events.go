@@ -0,0 +1,103 @@
+package manikyr
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// eventAggregator coalesces bursts of fsnotify events for a single root,
+// keyed by the path they concern. Every new event for a path resets that
+// path's idle timer instead of triggering work right away, so an editor's
+// "write temp + rename" or a batch copy only ever results in one flush.
+//
+// Every outstanding timer is tracked against wg, so a caller can block
+// until all of them have either fired or been cancelled via stop.
+type eventAggregator struct {
+	mu      sync.Mutex
+	pending map[string]fsnotify.Op
+	timers  map[string]*time.Timer
+	delay   time.Duration
+	wg      *sync.WaitGroup
+	flush   func(path string, op fsnotify.Op)
+	stopped bool
+}
+
+func newEventAggregator(delay time.Duration, wg *sync.WaitGroup, flush func(string, fsnotify.Op)) *eventAggregator {
+	return &eventAggregator{
+		pending: make(map[string]fsnotify.Op),
+		timers:  make(map[string]*time.Timer),
+		delay:   delay,
+		wg:      wg,
+		flush:   flush,
+	}
+}
+
+// add records op as the latest known state for path, (re)starting its
+// idle timer. Only the final op seen before the timer fires is flushed.
+func (a *eventAggregator) add(path string, op fsnotify.Op) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stopped {
+		return
+	}
+
+	a.pending[path] = op
+
+	if t, ok := a.timers[path]; ok {
+		if t.Stop() {
+			// Timer hadn't fired yet: reuse its wg slot, just push its
+			// deadline back out.
+			t.Reset(a.delay)
+			return
+		}
+		// Stop returned false: the timer already fired and its goroutine
+		// is running (or about to run) flushPath, which will claim this
+		// timer's wg slot on its own. Fall through and schedule a fresh
+		// timer (with its own wg slot) for the write we just recorded,
+		// rather than calling Reset on a timer that's mid-fire — doing
+		// so would schedule a second, unaccounted call to flushPath and
+		// double-Done the WaitGroup.
+	}
+
+	a.wg.Add(1)
+	a.timers[path] = time.AfterFunc(a.delay, func() {
+		a.flushPath(path)
+	})
+}
+
+func (a *eventAggregator) flushPath(path string) {
+	defer a.wg.Done()
+
+	a.mu.Lock()
+	op, ok := a.pending[path]
+	delete(a.pending, path)
+	delete(a.timers, path)
+	a.mu.Unlock()
+
+	if ok {
+		a.flush(path, op)
+	}
+}
+
+// stop cancels every outstanding timer and drops every pending op, so no
+// flush that hasn't started yet will run. Timers that already fired (or
+// are mid-flush) are left to finish on their own; the caller is expected
+// to wait on wg afterwards
+// to observe that completion.
+func (a *eventAggregator) stop() {
+	a.mu.Lock()
+	a.stopped = true
+	timers := a.timers
+	a.timers = make(map[string]*time.Timer)
+	a.pending = make(map[string]fsnotify.Op)
+	a.mu.Unlock()
+
+	for _, t := range timers {
+		if t.Stop() {
+			a.wg.Done()
+		}
+	}
+}
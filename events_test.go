@@ -0,0 +1,97 @@
+package manikyr
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TestEventAggregatorCoalescesBurst asserts that a burst of events for the
+// same path within the idle window results in exactly one flush, carrying
+// only the final op seen — the whole point of the aggregator.
+func TestEventAggregatorCoalescesBurst(t *testing.T) {
+	var wg sync.WaitGroup
+
+	var mu sync.Mutex
+	var flushes []fsnotify.Op
+
+	a := newEventAggregator(10*time.Millisecond, &wg, func(path string, op fsnotify.Op) {
+		mu.Lock()
+		flushes = append(flushes, op)
+		mu.Unlock()
+	})
+
+	a.add("f.txt", fsnotify.Create)
+	a.add("f.txt", fsnotify.Write)
+	a.add("f.txt", fsnotify.Write)
+	a.add("f.txt", fsnotify.Rename)
+
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(flushes) != 1 {
+		t.Fatalf("got %d flushes, want 1: %v", len(flushes), flushes)
+	}
+	if flushes[0] != fsnotify.Rename {
+		t.Fatalf("flushed op = %v, want %v (the last one seen)", flushes[0], fsnotify.Rename)
+	}
+}
+
+// TestEventAggregatorDistinctPaths asserts unrelated paths are debounced
+// independently rather than sharing a single timer/flush.
+func TestEventAggregatorDistinctPaths(t *testing.T) {
+	var wg sync.WaitGroup
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+
+	a := newEventAggregator(5*time.Millisecond, &wg, func(path string, op fsnotify.Op) {
+		mu.Lock()
+		seen[path]++
+		mu.Unlock()
+	})
+
+	a.add("a.txt", fsnotify.Write)
+	a.add("b.txt", fsnotify.Write)
+
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if seen["a.txt"] != 1 || seen["b.txt"] != 1 {
+		t.Fatalf("seen = %v, want exactly one flush per path", seen)
+	}
+}
+
+// TestEventAggregatorStopCancelsOutstandingTimers asserts stop prevents any
+// further flush and does not deadlock or double-count wg.
+func TestEventAggregatorStopCancelsOutstandingTimers(t *testing.T) {
+	var wg sync.WaitGroup
+
+	flushed := make(chan struct{}, 1)
+	a := newEventAggregator(50*time.Millisecond, &wg, func(path string, op fsnotify.Op) {
+		flushed <- struct{}{}
+	})
+
+	a.add("f.txt", fsnotify.Write)
+	a.stop()
+	wg.Wait()
+
+	select {
+	case <-flushed:
+		t.Fatalf("stop should have cancelled the timer before it flushed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// A stopped aggregator must silently ignore further events rather
+	// than starting new timers.
+	a.add("g.txt", fsnotify.Write)
+	select {
+	case <-flushed:
+		t.Fatalf("add after stop should be a no-op")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,132 @@
+package manikyr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRemoveRootDrainsPendingAggregator guards against a pending debounce
+// timer firing after RemoveRoot has returned: if it weren't cancelled, the
+// late flush could send on a channel the caller has since stopped
+// draining, or submit a job to a pool stopped by a later Close. Run with
+// -race to also catch the aggregator racing m.roots during teardown.
+func TestRemoveRootDrainsPendingAggregator(t *testing.T) {
+	dir := t.TempDir()
+
+	m := New()
+	m.SetEventDelay(5 * time.Millisecond)
+
+	evts := make(chan Event, 16)
+	if err := m.AddRoot(dir, evts); err != nil {
+		t.Fatalf("AddRoot: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// Give fsnotify a moment to deliver the event and start the
+	// aggregator's idle timer before we tear the root down.
+	time.Sleep(2 * time.Millisecond)
+
+	if err := m.RemoveRoot(dir); err != nil {
+		t.Fatalf("RemoveRoot: %v", err)
+	}
+
+	// RemoveRoot having returned must mean the timer above is either
+	// cancelled or has already finished flushing; nothing should still
+	// be pending to misfire once we walk away.
+	time.Sleep(3 * m.EventDelay())
+}
+
+func TestCloseClosesEventChannelsOnce(t *testing.T) {
+	dirA := t.TempDir()
+	dirB := t.TempDir()
+
+	m := New()
+	evts := make(chan Event, 16)
+
+	if err := m.AddRoot(dirA, evts); err != nil {
+		t.Fatalf("AddRoot(dirA): %v", err)
+	}
+	if err := m.AddRoot(dirB, evts); err != nil {
+		t.Fatalf("AddRoot(dirB): %v", err)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Any events queued before teardown may still be buffered, so drain
+	// past them; what Close guarantees is that the channel is eventually
+	// closed, not that it's empty.
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-evts:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected evts to be closed after Close")
+		}
+	}
+}
+
+// TestConcurrentRootChurnWhileEventsFlow guards m.roots itself: one root is
+// repeatedly added and removed while a second, unrelated root has files
+// written to it continuously. Every write dispatches through isIgnored,
+// which reads m.roots, so this exercises that read racing the
+// appends/slice-shifts done by AddRootContext/RemoveRoot. Run with -race.
+func TestConcurrentRootChurnWhileEventsFlow(t *testing.T) {
+	churnDir := t.TempDir()
+	busyDir := t.TempDir()
+
+	m := New()
+	m.SetEventDelay(time.Millisecond)
+	m.ShouldCreateThumb = func(root, file string) bool { return false }
+
+	evts := make(chan Event, 256)
+	if err := m.AddRoot(busyDir, evts); err != nil {
+		t.Fatalf("AddRoot(busyDir): %v", err)
+	}
+	go func() {
+		for range evts {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			name := filepath.Join(busyDir, fmt.Sprintf("f%d.txt", i))
+			os.WriteFile(name, []byte("x"), 0644)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(stop)
+		for i := 0; i < 200; i++ {
+			if err := m.AddRoot(churnDir, evts); err == nil {
+				m.RemoveRoot(churnDir)
+			}
+		}
+	}()
+
+	wg.Wait()
+	m.Close()
+}
@@ -3,16 +3,25 @@
 package manikyr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/disintegration/imaging"
-	"github.com/go-fsnotify/fsnotify"
+	"github.com/fsnotify/fsnotify"
 )
 
+// DefaultEventDelay is the idle window used to coalesce filesystem events
+// before a thumbnail is (re)created, unless overridden via SetEventDelay.
+const DefaultEventDelay = 10 * time.Second
+
 var (
 	ErrRootNotWatched   = errors.New("root is not watched")
 	ErrRootWatched      = errors.New("root is already watched")
@@ -40,6 +49,7 @@ const (
 	ThumbCreate
 	ThumbRemove
 	Watch
+	Overflow
 )
 func (t EventType) String() string {
 	switch t {
@@ -51,33 +61,44 @@ func (t EventType) String() string {
 			return "ThumbRemove"
 		case Watch:
 			return "Watch"
+		case Overflow:
+			return "Overflow"
 		default:
 			return "Unknown"
 	}
 }
 
-// Event represents a single event 
+// Event represents a single event
 // considering watching and thumbnailing files
 type Event struct {
-	Root  string
-	Path  string
-	Type  EventType
-	Error error
+	Root    string
+	Path    string
+	Type    EventType
+	Profile string
+	Error   error
 }
 
 // String returns a string representation of the event
 func (e Event) String() string {
+	name := e.Type.String()
+	if e.Profile != "" {
+		name = fmt.Sprintf("%s(%s)", name, e.Profile)
+	}
 	if e.Type == Error {
-		return fmt.Sprintf("%s: %s @ %s \\%s", e.Type.String(), e.Error.Error(), e.Path, e.Root)
+		return fmt.Sprintf("%s: %s @ %s \\%s", name, e.Error.Error(), e.Path, e.Root)
 	}
-	return fmt.Sprintf("%s: %s \\%s", e.Type.String(), e.Path, e.Root)
+	return fmt.Sprintf("%s: %s \\%s", name, e.Path, e.Root)
 }
 
 type rootWatcher struct {
-	path    string
-	watcher *fsnotify.Watcher
-	events	chan Event
-	done    chan struct{}
+	path       string
+	watcher    *fsnotify.Watcher
+	events	   chan Event
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	aggregator *eventAggregator
+	matcher    Matcher
 }
 
 // Manikyr watches specified directory roots for changes.
@@ -86,38 +107,56 @@ type rootWatcher struct {
 // to a dynamic location with the chosen dimensions and algorithm.
 // Subdirectory unwatching on deletion is automatic.
 type Manikyr struct {
+	rootsMu           sync.Mutex
 	roots             []*rootWatcher
 	thumbDirPerms     os.FileMode
 	thumbWidth        int
 	thumbHeight       int
 	thumbAlgo         imaging.ResampleFilter
-	ThumbDirGetter    func(string) string
-	ThumbNameGetter   func(string) string
+	ThumbDirGetter    func(string, *ThumbProfile) string
+	ThumbNameGetter   func(string, *ThumbProfile) string
 	ShouldCreateThumb func(string, string) bool
 	ShouldWatchSubdir func(string, string) bool
+	eventDelay        time.Duration
+	ignores           Matcher
+	pool              *workerPool
+	profiles          []*ThumbProfile
+	recursive         bool
 }
 
-func init() {
-	// Utilize all CPU cores for performance
-	runtime.GOMAXPROCS(runtime.NumCPU())
+func (m *Manikyr) EmitEvent(root string, t EventType, path string, err error) {
+	m.emitProfileEvent(root, t, path, "", err)
 }
 
-func (m *Manikyr) EmitEvent(root string, t EventType, path string, err error) {
+func (m *Manikyr) emitProfileEvent(root string, t EventType, path, profile string, err error) {
+	m.rootsMu.Lock()
+	var ch chan Event
 	for _, rw := range m.roots {
 		if rw.path == root {
-			rw.events <-Event{
-				Root: rw.path,
-				Type: t,
-				Path: path,
-				Error: err,
-			}
-			return
+			ch = rw.events
+			break
 		}
 	}
+	m.rootsMu.Unlock()
+
+	if ch == nil {
+		return
+	}
+
+	ch <-Event{
+		Root: root,
+		Type: t,
+		Path: path,
+		Profile: profile,
+		Error: err,
+	}
 }
 
 // Root returns a list of currently watched root directory paths.
 func (m *Manikyr) Roots() []string {
+	m.rootsMu.Lock()
+	defer m.rootsMu.Unlock()
+
 	roots := make([]string, len(m.roots))
 	for i, rw := range m.roots {
 		roots[i] = rw.path
@@ -126,6 +165,9 @@ func (m *Manikyr) Roots() []string {
 }
 
 func (m *Manikyr) HasRoot(root string) bool {
+	m.rootsMu.Lock()
+	defer m.rootsMu.Unlock()
+
 	for _, rw := range m.roots {
 		if rw.path == root {
 			return true
@@ -136,7 +178,17 @@ func (m *Manikyr) HasRoot(root string) bool {
 
 // AddRoot adds and watches specified path as a new root, piping future errors to given channel.
 // The error returned considers the watcher creation, not function.
+// Equivalent to AddRootContext with context.Background().
 func (m *Manikyr) AddRoot(root string, evtChan chan Event) error {
+	return m.AddRootContext(context.Background(), root, evtChan)
+}
+
+// AddRootContext adds and watches specified path as a new root, piping
+// future errors to given channel. The root's watch goroutine stops as
+// soon as ctx is done, whether that's through RemoveRoot, Close, or the
+// caller cancelling ctx itself. Thumbnail jobs already in flight are not
+// interrupted; RemoveRoot and Close wait for them to finish.
+func (m *Manikyr) AddRootContext(ctx context.Context, root string, evtChan chan Event) error {
 	if m.HasRoot(root) {
 		return ErrRootWatched
 	}
@@ -146,130 +198,266 @@ func (m *Manikyr) AddRoot(root string, evtChan chan Event) error {
 		return err
 	}
 
-	doneChan := make(chan struct{})
+	rootCtx, cancel := context.WithCancel(ctx)
 
 	rw := rootWatcher{
 		path: root,
 		events: evtChan,
-		done: doneChan,
 		watcher: w,
+		ctx: rootCtx,
+		cancel: cancel,
+	}
+	rw.aggregator = newEventAggregator(m.eventDelay, &rw.wg, func(evtPath string, op fsnotify.Op) {
+		m.dispatch(&rw, evtPath, op)
+	})
+
+	matcher, err := LoadMatcher(root)
+	if err != nil {
+		cancel()
+		return err
+	}
+	rw.matcher = matcher
+
+	if m.recursive {
+		if handled, err := m.watchRecursive(&rw, root); handled {
+			rw.wg.Add(1)
+			m.rootsMu.Lock()
+			m.roots = append(m.roots, &rw)
+			m.rootsMu.Unlock()
+			go m.watch(&rw)
+			return err
+		}
+		// Unsupported on this platform: fall through and watch root the
+		// usual way, relying on autoAdd/AddSubdir for its subtree.
 	}
 
+	rw.wg.Add(1)
+	m.rootsMu.Lock()
 	m.roots = append(m.roots, &rw)
+	m.rootsMu.Unlock()
 	go m.watch(&rw)
 
 	return rw.watcher.Add(root)
 }
 
-// RemoveRoot removes the named root directory path and unwatches it. 
+// RemoveRoot removes the named root directory path and unwatches it.
 // A root should always be unwatched this way prior to actual
 // path deletion in the filesystem.
 // If the named path was not previously specified to be a root,
-// a non-nil error is returned.
+// a non-nil error is returned. It blocks until the root's watch
+// goroutine and any in-flight thumbnail jobs for it have drained.
 func (m *Manikyr) RemoveRoot(root string) error {
-	if !m.HasRoot(root) {
+	m.rootsMu.Lock()
+	var target *rootWatcher
+	for _, rw := range m.roots {
+		if rw.path == root {
+			target = rw
+			break
+		}
+	}
+	m.rootsMu.Unlock()
+
+	if target == nil {
 		return ErrRootNotWatched
 	}
 
+	// Tear down outside the lock: wg.Wait can block on in-flight jobs
+	// that themselves need to read m.roots (e.g. emitProfileEvent),
+	// and holding rootsMu here would deadlock against them.
+	target.cancel()
+	target.aggregator.stop()
+	target.watcher.Close()
+	target.wg.Wait()
+
+	m.rootsMu.Lock()
 	for i, rw := range m.roots {
-		if rw.path == root {
-			rw.watcher.Close()
-			rw.done <- struct{}{}
+		if rw == target {
 			m.roots = append(m.roots[:i], m.roots[i+1:]...)
 			break
 		}
 	}
+	m.rootsMu.Unlock()
 
 	return nil
 }
 
+// Close removes and unwatches every root, waits for their watch goroutines
+// and any in-flight thumbnail jobs to drain, and closes each distinct
+// event channel so consumers ranging over them return cleanly.
+func (m *Manikyr) Close() error {
+	roots := m.Roots()
+
+	m.rootsMu.Lock()
+	seen := make(map[chan Event]bool)
+	var chans []chan Event
+	for _, rw := range m.roots {
+		if !seen[rw.events] {
+			seen[rw.events] = true
+			chans = append(chans, rw.events)
+		}
+	}
+	m.rootsMu.Unlock()
+
+	var firstErr error
+	for _, root := range roots {
+		if err := m.RemoveRoot(root); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	m.pool.closeAll()
+
+	for _, ch := range chans {
+		close(ch)
+	}
+
+	return firstErr
+}
+
 func (m *Manikyr) watch(rw *rootWatcher) {
+	defer rw.wg.Done()
 	defer rw.watcher.Close()
 	for {
 		select {
 		case evt := <-rw.watcher.Events:
-			if evt.Op == fsnotify.Create {
-				// If a file was created
-
-				// Get some info about the file
-				info, err := os.Stat(evt.Name)
-				if os.IsNotExist(err) {
-					m.EmitEvent(rw.path, Error, evt.Name, err)
-					continue
-				}
-
-				switch mode := info.Mode(); {
-				case mode.IsDir():
-					if m.ShouldWatchSubdir(rw.path, evt.Name) {
-						rw.watcher.Add(evt.Name)
-					}
-				case mode.IsRegular():
-					if m.ShouldCreateThumb(rw.path, evt.Name) {
-						go m.createThumb(rw.path, evt.Name)
-					}
-				}
-			} else {
-				// Something else happened to the file
-				_, err := os.Stat(evt.Name)
-				if os.IsNotExist(err) {
-					// Try to delete thumb.
-					m.removeThumb(rw.path, evt.Name)
-					continue
-				} else if err != nil {
-					m.EmitEvent(rw.path, Error, evt.Name, err)
-					continue
-				}
-			}
+			rw.aggregator.add(evt.Name, evt.Op)
 		case err := <-rw.watcher.Errors:
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// The kernel buffer overflowed and events were dropped;
+				// reconcile by re-scanning the root as if freshly added.
+				m.EmitEvent(rw.path, Overflow, rw.path, err)
+				go autoAdd(m, rw.path, rw.path)
+				continue
+			}
 			m.EmitEvent(rw.path, Error, "", err)
-		case <-rw.done:
+		case <-rw.ctx.Done():
+			return
+		}
+	}
+}
+
+// submitJob runs job on the worker pool, tracking it against rw's
+// WaitGroup so RemoveRoot/Close can wait for it to finish draining.
+func (m *Manikyr) submitJob(rw *rootWatcher, job func()) {
+	rw.wg.Add(1)
+	m.pool.submit(func() {
+		defer rw.wg.Done()
+		job()
+	})
+}
+
+// submitRootJob is submitJob for callers that only have a root path, such
+// as the autoAdd walk.
+func (m *Manikyr) submitRootJob(root string, job func()) {
+	m.rootsMu.Lock()
+	var target *rootWatcher
+	for _, rw := range m.roots {
+		if rw.path == root {
+			target = rw
 			break
 		}
 	}
+	m.rootsMu.Unlock()
+
+	if target != nil {
+		m.submitJob(target, job)
+		return
+	}
+	m.pool.submit(job)
+}
+
+// dispatch acts on the final, coalesced state of a path once its event
+// aggregator idle window has elapsed.
+func (m *Manikyr) dispatch(rw *rootWatcher, evtPath string, op fsnotify.Op) {
+	if op.Has(fsnotify.Remove) {
+		m.submitJob(rw, func() { m.removeThumb(rw.path, evtPath) })
+		return
+	}
+
+	if !op.Has(fsnotify.Create) && !op.Has(fsnotify.Write) && !op.Has(fsnotify.Rename) {
+		// Chmod-only (or otherwise uninteresting) changes are ignored.
+		return
+	}
+
+	info, err := os.Stat(evtPath)
+	if os.IsNotExist(err) {
+		m.submitJob(rw, func() { m.removeThumb(rw.path, evtPath) })
+		return
+	} else if err != nil {
+		m.EmitEvent(rw.path, Error, evtPath, err)
+		return
+	}
+
+	switch mode := info.Mode(); {
+	case mode.IsDir():
+		if m.shouldWatchSubdir(rw.path, evtPath) {
+			rw.watcher.Add(evtPath)
+		}
+	case mode.IsRegular():
+		if m.shouldCreateThumb(rw.path, evtPath) {
+			m.submitJob(rw, func() { m.createThumb(rw.path, evtPath) })
+		}
+	}
 }
 
 // AddSubdir adds a subdirectory to a root watcher. 
 // Both paths should be absolute.
 func (m *Manikyr) AddSubdir(root, subdir string) {
+	m.rootsMu.Lock()
+	var target *rootWatcher
 	for _, rw := range m.roots {
 		if rw.path == root {
-			err := rw.watcher.Add(subdir)
-			if err != nil {
-				m.EmitEvent(root, Error, subdir, err)
-				return
-			}
-			m.EmitEvent(root, Watch, subdir, nil)
-			return
+			target = rw
+			break
 		}
 	}
+	m.rootsMu.Unlock()
+
+	if target == nil {
+		m.EmitEvent(root, Error, subdir, ErrRootNotWatched)
+		return
+	}
 
-	m.EmitEvent(root, Error, subdir, ErrRootNotWatched)
+	if err := target.watcher.Add(subdir); err != nil {
+		m.EmitEvent(root, Error, subdir, err)
+		return
+	}
+	m.EmitEvent(root, Watch, subdir, nil)
 }
 
 // RemoveSubdir removes a subdirectory from a root watcher.
 // Both paths should be absolute.
 func (m *Manikyr) RemoveSubdir(root, subdir string) error {
+	m.rootsMu.Lock()
+	var target *rootWatcher
 	for _, rw := range m.roots {
 		if rw.path == root {
-			return rw.watcher.Remove(subdir)
+			target = rw
+			break
 		}
 	}
+	m.rootsMu.Unlock()
 
-	return ErrRootNotWatched
+	if target == nil {
+		return ErrRootNotWatched
+	}
+	return target.watcher.Remove(subdir)
 }
 
 func (m *Manikyr) removeThumb(root, parentFile string) {
-	thumbPath := path.Join(m.ThumbDirGetter(parentFile), m.ThumbNameGetter(parentFile))
-	err := os.Remove(thumbPath)
+	for _, p := range m.activeProfiles() {
+		thumbPath := withFormat(path.Join(m.ThumbDirGetter(parentFile, p), m.ThumbNameGetter(parentFile, p)), p.Format)
+		err := os.Remove(thumbPath)
+
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			m.emitProfileEvent(root, Error, thumbPath, p.Name, err)
+			continue
+		}
 
-	if os.IsNotExist(err) {
-		return
-	} else if err != nil {
-		m.EmitEvent(root, Error, thumbPath, err)
-		return
+		m.emitProfileEvent(root, ThumbRemove, thumbPath, p.Name, nil)
 	}
-
-	m.EmitEvent(root, ThumbRemove, thumbPath, nil)
 }
 
 func (m *Manikyr) createThumb(root, parentFile string) {
@@ -279,30 +467,51 @@ func (m *Manikyr) createThumb(root, parentFile string) {
 		return
 	}
 
-	localThumbs := m.ThumbDirGetter(parentFile)
-	_, err = os.Stat(localThumbs)
-	// If thumbDir does not exist...
-	if os.IsNotExist(err) {
-		// ..create it
-		err := os.Mkdir(localThumbs, m.thumbDirPerms)
-		if err != nil {
-			m.EmitEvent(root, Error, localThumbs, err)
-			return
+	for _, p := range m.activeProfiles() {
+		localThumbs := m.ThumbDirGetter(parentFile, p)
+		_, err := os.Stat(localThumbs)
+		// If thumbDir does not exist...
+		if os.IsNotExist(err) {
+			// ..create it
+			if err := os.Mkdir(localThumbs, m.thumbDirPerms); err != nil {
+				m.emitProfileEvent(root, Error, localThumbs, p.Name, err)
+				continue
+			}
+		} else if err != nil {
+			m.emitProfileEvent(root, Error, localThumbs, p.Name, err)
+			continue
 		}
-	} else if err != nil {
-		m.EmitEvent(root, Error, localThumbs, err)
-		return
+
+		// Save the thumbnail
+		thumb := imaging.Thumbnail(img, p.Width, p.Height, p.Algo)
+		thumbPath := withFormat(path.Join(localThumbs, m.ThumbNameGetter(parentFile, p)), p.Format)
+		if err := imaging.Save(thumb, thumbPath); err != nil {
+			m.emitProfileEvent(root, Error, thumbPath, p.Name, err)
+			continue
+		}
+
+		m.emitProfileEvent(root, ThumbCreate, thumbPath, p.Name, nil)
 	}
+}
 
-	// Save the thumbnail
-	thumb := imaging.Thumbnail(img, m.thumbWidth, m.thumbHeight, m.thumbAlgo)
-	thumbPath := path.Join(localThumbs, m.ThumbNameGetter(parentFile))
-	if err = imaging.Save(thumb, thumbPath); err != nil {
-		m.EmitEvent(root, Error, thumbPath, err)
-		return
+// missingAnyThumb reports whether parentFile is missing the thumbnail for
+// at least one active profile.
+func (m *Manikyr) missingAnyThumb(parentFile string) bool {
+	for _, p := range m.activeProfiles() {
+		thumbPath := withFormat(path.Join(m.ThumbDirGetter(parentFile, p), m.ThumbNameGetter(parentFile, p)), p.Format)
+		if _, err := os.Stat(thumbPath); os.IsNotExist(err) {
+			return true
+		}
 	}
+	return false
+}
 
-	m.EmitEvent(root, ThumbCreate, thumbPath, nil)
+// withFormat swaps p's file extension for format, when format is set.
+func withFormat(p, format string) string {
+	if format == "" {
+		return p
+	}
+	return strings.TrimSuffix(p, path.Ext(p)) + "." + format
 }
 
 // Get the currently set thumbnail dimensions
@@ -345,6 +554,100 @@ func (m *Manikyr) SetThumbAlgorithm(filter imaging.ResampleFilter) {
 	m.thumbAlgo = filter
 }
 
+// EventDelay returns the currently set idle window events are coalesced over
+// before a thumbnail is created or removed.
+func (m *Manikyr) EventDelay() time.Duration {
+	return m.eventDelay
+}
+
+// SetEventDelay sets the idle window filesystem events for a given path are
+// coalesced over before being acted on. A shorter delay reacts faster but
+// risks reading files that are still being written to; a longer delay is
+// more forgiving of editors and bulk copies at the cost of latency.
+func (m *Manikyr) SetEventDelay(d time.Duration) {
+	m.eventDelay = d
+}
+
+// Recursive reports whether AddRoot attempts to establish a single native
+// recursive watch instead of walking and watching each subdirectory.
+func (m *Manikyr) Recursive() bool {
+	return m.recursive
+}
+
+// SetRecursive toggles whether newly added roots are watched recursively
+// via the platform's native support (currently Windows only). On
+// platforms without native recursive watches, roots keep being watched
+// through the usual per-subdirectory walk regardless of this setting.
+func (m *Manikyr) SetRecursive(r bool) {
+	m.recursive = r
+}
+
+// Workers returns the number of goroutines currently used to run
+// thumbnail creation and removal jobs.
+func (m *Manikyr) Workers() int {
+	return m.pool.size()
+}
+
+// SetWorkers sets the number of goroutines used to run thumbnail creation
+// and removal jobs. Values below 1 are treated as 1.
+func (m *Manikyr) SetWorkers(n int) {
+	m.pool.resize(n)
+}
+
+// SetIgnores sets a list of gitignore/stignore-style patterns that apply
+// across all roots, in addition to any .manikyrignore file discovered
+// under each individual root.
+func (m *Manikyr) SetIgnores(patterns []string) {
+	m.ignores = NewMatcher(patterns)
+}
+
+// isIgnored reports whether p, an absolute path under root, is ignored by
+// the global patterns set via SetIgnores or by root's .manikyrignore file.
+func (m *Manikyr) isIgnored(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	if m.ignores != nil && m.ignores.ShouldIgnore(rel) {
+		return true
+	}
+
+	m.rootsMu.Lock()
+	var matcher Matcher
+	for _, rw := range m.roots {
+		if rw.path == root {
+			matcher = rw.matcher
+			break
+		}
+	}
+	m.rootsMu.Unlock()
+
+	if matcher != nil {
+		return matcher.ShouldIgnore(rel)
+	}
+	return false
+}
+
+// shouldWatchSubdir combines the ignore Matchers with the user-provided
+// ShouldWatchSubdir hook, which acts as an additional filter on top.
+func (m *Manikyr) shouldWatchSubdir(root, subdir string) bool {
+	if m.isIgnored(root, subdir) {
+		return false
+	}
+	return m.ShouldWatchSubdir(root, subdir)
+}
+
+// shouldCreateThumb combines the ignore Matchers with the user-provided
+// ShouldCreateThumb hook, which acts as an additional filter on top.
+func (m *Manikyr) shouldCreateThumb(root, file string) bool {
+	if m.isIgnored(root, file) {
+		return false
+	}
+	return m.ShouldCreateThumb(root, file)
+}
+
 // Init watches and thumbnail existing files as if they
 // were added after the root directory got watched.
 // Regular files are checked for corresponding thumbnails
@@ -369,10 +672,12 @@ func New() *Manikyr {
 		thumbHeight:   128,
 		thumbAlgo:     NearestNeighbor,
 		thumbDirPerms: 0777,
-		ThumbDirGetter: func(parentFile string) string {
+		eventDelay:    DefaultEventDelay,
+		pool:          newWorkerPool(runtime.NumCPU()),
+		ThumbDirGetter: func(parentFile string, profile *ThumbProfile) string {
 			return os.TempDir()
 		},
-		ThumbNameGetter: func(parentFile string) string {
+		ThumbNameGetter: func(parentFile string, profile *ThumbProfile) string {
 			return path.Base(parentFile)
 		},
 		ShouldCreateThumb: func(root, parentFile string) bool {
@@ -0,0 +1,140 @@
+package manikyr
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// IgnoreFile is the name of the optional, per-root file Matcher patterns
+// are discovered from, one pattern per line, gitignore/stignore-style.
+const IgnoreFile = ".manikyrignore"
+
+// Matcher decides whether a path relative to a watched root should be
+// ignored for both subdirectory watching and thumbnail creation.
+type Matcher interface {
+	ShouldIgnore(rel string) bool
+}
+
+// patternMatcher is a Matcher backed by a list of gitignore/stignore-style
+// patterns: a leading "!" negates a match, "**" matches arbitrary depth,
+// and a leading "/" anchors the pattern to the root. A trailing "/" is
+// accepted and stripped for compatibility, but since ShouldIgnore only
+// ever sees a path string (never a file type), it isn't used to restrict
+// a match to directories; any matched path already ignores everything
+// beneath it regardless of a trailing slash.
+type patternMatcher struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	raw      string
+	negate   bool
+	anchored bool
+}
+
+// NewMatcher builds a Matcher from gitignore/stignore-style pattern lines.
+// Blank lines and lines starting with "#" are ignored.
+func NewMatcher(patterns []string) Matcher {
+	pm := &patternMatcher{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		ip := ignorePattern{}
+		if strings.HasPrefix(p, "!") {
+			ip.negate = true
+			p = p[1:]
+		}
+		if strings.HasPrefix(p, "/") {
+			ip.anchored = true
+			p = p[1:]
+		}
+		p = strings.TrimSuffix(p, "/")
+		ip.raw = p
+
+		pm.patterns = append(pm.patterns, ip)
+	}
+	return pm
+}
+
+// LoadMatcher reads patterns from the IgnoreFile under root, if present.
+// A missing ignore file is not an error; it simply yields an empty Matcher.
+func LoadMatcher(root string) (Matcher, error) {
+	contents, err := ioutil.ReadFile(path.Join(root, IgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewMatcher(nil), nil
+		}
+		return nil, err
+	}
+	return NewMatcher(strings.Split(string(contents), "\n")), nil
+}
+
+// ShouldIgnore reports whether rel, a slash-separated path relative to the
+// root the Matcher was built for, should be ignored. Later patterns take
+// precedence over earlier ones, mirroring gitignore semantics.
+func (pm *patternMatcher) ShouldIgnore(rel string) bool {
+	ignored := false
+	for _, ip := range pm.patterns {
+		if ip.matches(rel) {
+			ignored = !ip.negate
+		}
+	}
+	return ignored
+}
+
+func (ip ignorePattern) matches(rel string) bool {
+	segs := strings.Split(strings.Trim(rel, "/"), "/")
+	patSegs := strings.Split(ip.raw, "/")
+
+	// An anchored pattern must line up with the start of rel; an
+	// unanchored one may match starting at any depth.
+	start, end := 0, 0
+	if !ip.anchored {
+		end = len(segs)
+	}
+	for ; start <= end; start++ {
+		if matchSegments(patSegs, segs[start:]) {
+			return true
+		}
+		// Per gitignore semantics, a pattern matching a directory also
+		// ignores everything beneath it. Matcher is only given a path
+		// string, not its file type, so this is checked regardless of
+		// whether the pattern was "/"-suffixed.
+		for i := start + 1; i <= len(segs); i++ {
+			if matchSegments(patSegs, segs[start:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments matches path-separated pattern segments against
+// path-separated segments of a candidate path, treating a "**" segment
+// as matching zero or more path segments.
+func matchSegments(patSegs, pathSegs []string) bool {
+	if len(patSegs) == 0 {
+		return len(pathSegs) == 0
+	}
+	if patSegs[0] == "**" {
+		if matchSegments(patSegs[1:], pathSegs) {
+			return true
+		}
+		if len(pathSegs) == 0 {
+			return false
+		}
+		return matchSegments(patSegs, pathSegs[1:])
+	}
+	if len(pathSegs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(patSegs[0], pathSegs[0]); !ok {
+		return false
+	}
+	return matchSegments(patSegs[1:], pathSegs[1:])
+}
@@ -0,0 +1,72 @@
+package manikyr
+
+import "testing"
+
+func TestMatcherBasicMatch(t *testing.T) {
+	m := NewMatcher([]string{"build"})
+	if !m.ShouldIgnore("build") {
+		t.Fatalf("expected build to be ignored")
+	}
+	if m.ShouldIgnore("src") {
+		t.Fatalf("expected src to not be ignored")
+	}
+}
+
+// TestMatcherIgnoresMatchedDirectoryContents guards gitignore semantics: a
+// pattern matching a directory excludes everything beneath it, regardless
+// of whether the pattern itself ends in "/".
+func TestMatcherIgnoresMatchedDirectoryContents(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{[]string{"build"}, "build/file.txt", true},
+		{[]string{"**/node_modules"}, "sub/node_modules/x.js", true},
+		{[]string{"build/"}, "build/file.txt", true},
+		{[]string{"build"}, "other/file.txt", false},
+	}
+	for _, c := range cases {
+		got := NewMatcher(c.patterns).ShouldIgnore(c.path)
+		if got != c.want {
+			t.Errorf("NewMatcher(%v).ShouldIgnore(%q) = %v, want %v", c.patterns, c.path, got, c.want)
+		}
+	}
+}
+
+func TestMatcherWildcardDepth(t *testing.T) {
+	m := NewMatcher([]string{"**/*.jpg"})
+	if !m.ShouldIgnore("a/b/c/photo.jpg") {
+		t.Fatalf("expected nested .jpg to be ignored")
+	}
+	if m.ShouldIgnore("a/b/c/photo.png") {
+		t.Fatalf("expected .png to not be ignored")
+	}
+}
+
+func TestMatcherAnchored(t *testing.T) {
+	m := NewMatcher([]string{"/build"})
+	if !m.ShouldIgnore("build") {
+		t.Fatalf("expected anchored pattern to match at root")
+	}
+	if m.ShouldIgnore("sub/build") {
+		t.Fatalf("expected anchored pattern to not match nested build")
+	}
+}
+
+func TestMatcherNegationOverridesEarlierMatch(t *testing.T) {
+	m := NewMatcher([]string{"*.log", "!keep.log"})
+	if m.ShouldIgnore("keep.log") {
+		t.Fatalf("expected keep.log to be un-ignored by negation")
+	}
+	if !m.ShouldIgnore("other.log") {
+		t.Fatalf("expected other.log to still be ignored")
+	}
+}
+
+func TestMatcherBlankAndCommentLinesSkipped(t *testing.T) {
+	m := NewMatcher([]string{"", "  ", "# comment", "build"})
+	if !m.ShouldIgnore("build") {
+		t.Fatalf("expected build to be ignored despite blank/comment lines")
+	}
+}
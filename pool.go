@@ -0,0 +1,81 @@
+package manikyr
+
+import "sync"
+
+// workerPool runs submitted jobs across a bounded number of goroutines,
+// so a large Init walk or a burst of Create events can't spawn one
+// goroutine (and one decode/encode) per file.
+type workerPool struct {
+	mu      sync.Mutex
+	jobs    chan func()
+	workers int
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newWorkerPool(n int) *workerPool {
+	p := &workerPool{jobs: make(chan func())}
+	p.resize(n)
+	return p
+}
+
+// resize stops the current workers, if any, and starts n new ones.
+func (p *workerPool) resize(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.wg.Wait()
+	}
+
+	p.workers = n
+	p.stop = make(chan struct{})
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go p.work(p.stop)
+	}
+}
+
+func (p *workerPool) work(stop chan struct{}) {
+	defer p.wg.Done()
+	for {
+		select {
+		case job := <-p.jobs:
+			job()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// submit hands job to the next free worker. The jobs channel is
+// unbuffered, so it blocks while every worker is busy.
+func (p *workerPool) submit(job func()) {
+	p.jobs <- job
+}
+
+func (p *workerPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.workers
+}
+
+// closeAll stops every worker and waits for it to exit. The pool is left
+// idle; submit will block forever until resize starts workers again.
+func (p *workerPool) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+	p.stop = nil
+	p.workers = 0
+}
@@ -0,0 +1,51 @@
+package manikyr
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWorkerPoolResize(t *testing.T) {
+	p := newWorkerPool(2)
+	if got := p.size(); got != 2 {
+		t.Fatalf("size() = %d, want 2", got)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p.submit(func() { wg.Done() })
+	wg.Wait()
+
+	p.resize(4)
+	if got := p.size(); got != 4 {
+		t.Fatalf("size() after resize = %d, want 4", got)
+	}
+
+	wg.Add(1)
+	p.submit(func() { wg.Done() })
+	wg.Wait()
+}
+
+func TestWorkerPoolResizeBelowOne(t *testing.T) {
+	p := newWorkerPool(0)
+	if got := p.size(); got != 1 {
+		t.Fatalf("size() = %d, want 1 (clamped)", got)
+	}
+}
+
+func TestWorkerPoolCloseAll(t *testing.T) {
+	p := newWorkerPool(3)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p.submit(func() { wg.Done() })
+	wg.Wait()
+
+	p.closeAll()
+	if got := p.size(); got != 0 {
+		t.Fatalf("size() after closeAll = %d, want 0", got)
+	}
+
+	// closeAll on an already-closed pool must not block or panic.
+	p.closeAll()
+}
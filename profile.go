@@ -0,0 +1,80 @@
+package manikyr
+
+import (
+	"errors"
+
+	"github.com/disintegration/imaging"
+)
+
+var (
+	ErrProfileNameRequired      = errors.New("thumbnail profile name is required")
+	ErrProfileExists            = errors.New("thumbnail profile is already registered")
+	ErrProfileFormatUnsupported = errors.New("thumbnail profile format is not supported by imaging")
+)
+
+// ThumbProfile describes one thumbnail variant to generate per source
+// image, e.g. a small preview alongside a larger full-size rendition.
+type ThumbProfile struct {
+	Name   string
+	Width  int
+	Height int
+	Algo   imaging.ResampleFilter
+	// Format, if set, overrides the output file extension/encoding; it
+	// must be one of the formats imaging can encode ("jpg"/"jpeg",
+	// "png", "gif", "tif"/"tiff", "bmp"). Left empty it matches the
+	// source file.
+	Format string
+}
+
+// Profiles returns the currently registered thumbnail profiles, in
+// registration order. An empty result means the single default profile
+// configured via SetThumbSize/SetThumbAlgorithm is used instead.
+func (m *Manikyr) Profiles() []*ThumbProfile {
+	profiles := make([]*ThumbProfile, len(m.profiles))
+	copy(profiles, m.profiles)
+	return profiles
+}
+
+// RegisterProfile adds a named thumbnail variant to be generated
+// alongside any others already registered. Name must be unique and
+// non-empty; Width/Height below 1 are treated as 1.
+func (m *Manikyr) RegisterProfile(p ThumbProfile) error {
+	if p.Name == "" {
+		return ErrProfileNameRequired
+	}
+	for _, existing := range m.profiles {
+		if existing.Name == p.Name {
+			return ErrProfileExists
+		}
+	}
+
+	if p.Format != "" {
+		if _, err := imaging.FormatFromExtension(p.Format); err != nil {
+			return ErrProfileFormatUnsupported
+		}
+	}
+
+	if p.Width < 1 {
+		p.Width = 1
+	}
+	if p.Height < 1 {
+		p.Height = 1
+	}
+
+	m.profiles = append(m.profiles, &p)
+	return nil
+}
+
+// activeProfiles returns the registered profiles, or a single unnamed
+// profile built from the legacy SetThumbSize/SetThumbAlgorithm settings
+// if none have been registered.
+func (m *Manikyr) activeProfiles() []*ThumbProfile {
+	if len(m.profiles) > 0 {
+		return m.profiles
+	}
+	return []*ThumbProfile{{
+		Width:  m.thumbWidth,
+		Height: m.thumbHeight,
+		Algo:   m.thumbAlgo,
+	}}
+}
@@ -0,0 +1,78 @@
+package manikyr
+
+import "testing"
+
+func TestRegisterProfileRequiresName(t *testing.T) {
+	m := New()
+	if err := m.RegisterProfile(ThumbProfile{}); err != ErrProfileNameRequired {
+		t.Fatalf("got %v, want ErrProfileNameRequired", err)
+	}
+}
+
+func TestRegisterProfileRejectsDuplicateName(t *testing.T) {
+	m := New()
+	if err := m.RegisterProfile(ThumbProfile{Name: "small"}); err != nil {
+		t.Fatalf("first RegisterProfile: %v", err)
+	}
+	if err := m.RegisterProfile(ThumbProfile{Name: "small"}); err != ErrProfileExists {
+		t.Fatalf("got %v, want ErrProfileExists", err)
+	}
+}
+
+func TestRegisterProfileRejectsUnsupportedFormat(t *testing.T) {
+	m := New()
+	if err := m.RegisterProfile(ThumbProfile{Name: "p", Format: "webp"}); err != ErrProfileFormatUnsupported {
+		t.Fatalf("got %v, want ErrProfileFormatUnsupported", err)
+	}
+	if err := m.RegisterProfile(ThumbProfile{Name: "p", Format: "png"}); err != nil {
+		t.Fatalf("expected png to be accepted, got %v", err)
+	}
+}
+
+func TestRegisterProfileClampsDimensions(t *testing.T) {
+	m := New()
+	if err := m.RegisterProfile(ThumbProfile{Name: "p", Width: 0, Height: -5}); err != nil {
+		t.Fatalf("RegisterProfile: %v", err)
+	}
+	got := m.Profiles()[0]
+	if got.Width != 1 || got.Height != 1 {
+		t.Fatalf("got Width=%d Height=%d, want both clamped to 1", got.Width, got.Height)
+	}
+}
+
+func TestProfilesReturnsCopy(t *testing.T) {
+	m := New()
+	if err := m.RegisterProfile(ThumbProfile{Name: "p"}); err != nil {
+		t.Fatalf("RegisterProfile: %v", err)
+	}
+	got := m.Profiles()
+	got[0] = nil
+	if m.Profiles()[0] == nil {
+		t.Fatalf("mutating the returned slice must not affect the registered profiles")
+	}
+}
+
+func TestActiveProfilesFallsBackToLegacySettings(t *testing.T) {
+	m := New()
+	m.SetThumbSize(32, 64)
+
+	profiles := m.activeProfiles()
+	if len(profiles) != 1 {
+		t.Fatalf("got %d profiles, want 1 fallback profile", len(profiles))
+	}
+	if profiles[0].Width != 32 || profiles[0].Height != 64 {
+		t.Fatalf("fallback profile = %+v, want Width=32 Height=64", profiles[0])
+	}
+}
+
+func TestActiveProfilesPrefersRegistered(t *testing.T) {
+	m := New()
+	if err := m.RegisterProfile(ThumbProfile{Name: "p", Width: 10, Height: 10}); err != nil {
+		t.Fatalf("RegisterProfile: %v", err)
+	}
+
+	profiles := m.activeProfiles()
+	if len(profiles) != 1 || profiles[0].Name != "p" {
+		t.Fatalf("got %+v, want the single registered profile", profiles)
+	}
+}
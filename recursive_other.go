@@ -0,0 +1,18 @@
+//go:build !windows
+
+package manikyr
+
+import "errors"
+
+// ErrRecursionUnsupported is returned internally when the platform's
+// fsnotify backend (inotify, kqueue, ...) has no native recursive watch,
+// so callers of AddRoot fall back to the explicit per-subdirectory walk
+// transparently instead of surfacing this as a hard failure.
+var ErrRecursionUnsupported = errors.New("recursive watching is not supported on this platform")
+
+// watchRecursive reports that this platform has no native recursive
+// watch; the caller falls back to watching root non-recursively and
+// relying on the usual autoAdd/AddSubdir walk to cover its subtree.
+func (m *Manikyr) watchRecursive(rw *rootWatcher, root string) (bool, error) {
+	return false, ErrRecursionUnsupported
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package manikyr
+
+// watchRecursive establishes a native recursive watch on root. On Windows,
+// fsnotify's ReadDirectoryChangesW backend watches an entire subtree from
+// a single Add call, so no per-subdirectory walk is needed.
+func (m *Manikyr) watchRecursive(rw *rootWatcher, root string) (bool, error) {
+	return true, rw.watcher.Add(root)
+}
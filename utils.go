@@ -3,39 +3,18 @@ package manikyr
 import (
 	"image"
 	"io/ioutil"
-	"os"
 	"path"
 	"strings"
-	"time"
-	
+
 	"github.com/disintegration/imaging"
 )
 
+// openImageWhenReady opens file as an image. It used to retry with a growing
+// sleep to paper over editors still writing the file; now that events are
+// coalesced over an idle window (see eventAggregator), the file is expected
+// to be complete by the time this is called.
 func openImageWhenReady(file string) (image.Image, error) {
-	// Retry opening the image until err != image.ErrFormat
-	// or the next retry would take over a minute.
-	// FIXME
-	
-	var img image.Image
-	var err error
-	var retry int
-	var t time.Duration
-
-	for {
-		t = time.Duration(1000 * (retry * 2))
-		time.Sleep(time.Millisecond * t)
-
-		img, err = imaging.Open(file)
-		if err == image.ErrFormat {
-			retry = retry + 1
-			if retry*2 > 60 {
-				break
-			}
-			continue
-		}
-		break
-	}
-	return img, err
+	return imaging.Open(file)
 }
 
 func Subdirectories(root string) ([]string, error) {
@@ -66,18 +45,13 @@ func autoAdd(m *Manikyr, root, currentDir string) {
 	}
 
 	for _, file := range files {
-		filePath := path.Join(root, file.Name())
-		if file.IsDir() && m.ShouldWatchSubdir(currentDir, filePath) {
+		filePath := path.Join(currentDir, file.Name())
+		if file.IsDir() && m.shouldWatchSubdir(root, filePath) {
 			m.AddSubdir(root, filePath)
 			autoAdd(m, root, filePath)
-		} else if !file.IsDir() && m.ShouldCreateThumb(root, filePath) {
-			println(2)
-			thumbLocation := path.Join(m.ThumbDirGetter(filePath), m.ThumbNameGetter(filePath))
-			if _, err := os.Stat(thumbLocation); os.IsNotExist(err) {
-				go m.createThumb(root, filePath)
-			} else if err != nil {
-				m.EmitEvent(root, Error, filePath, err)
-				continue
+		} else if !file.IsDir() && m.shouldCreateThumb(root, filePath) {
+			if m.missingAnyThumb(filePath) {
+				m.submitRootJob(root, func() { m.createThumb(root, filePath) })
 			}
 		}
 	}